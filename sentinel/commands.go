@@ -0,0 +1,79 @@
+package sentinel
+
+import (
+	"strings"
+
+	"github.com/alicebob/miniredis/server"
+)
+
+// commandsPing registers the commands every sentinel connection can use
+// regardless of which master(s) it asks about: PING, AUTH, and the SENTINEL
+// command family itself.
+func commandsPing(s *Sentinel) {
+	s.srv.Register("PING", func(c *server.Peer, cmd string, args []string) {
+		if !s.handleAuth(c) {
+			return
+		}
+		c.WriteInline("PONG")
+	})
+
+	s.srv.Register("AUTH", func(c *server.Peer, cmd string, args []string) {
+		if len(args) != 1 {
+			c.WriteError(errWrongNumber(cmd))
+			return
+		}
+		s.Lock()
+		pw := s.password
+		s.Unlock()
+		if pw == "" {
+			c.WriteError("ERR Client sent AUTH, but no password is set")
+			return
+		}
+		if args[0] != pw {
+			c.WriteError("ERR invalid password")
+			return
+		}
+		setAuthenticated(c)
+		c.WriteOK()
+	})
+
+	s.srv.Register("SENTINEL", func(c *server.Peer, cmd string, args []string) {
+		if !s.handleAuth(c) {
+			return
+		}
+		if len(args) < 1 {
+			c.WriteError(errWrongNumber(cmd))
+			return
+		}
+		sub := strings.ToUpper(args[0])
+		rest := args[1:]
+		switch sub {
+		case "MASTERS":
+			s.cmdSentinelMasters(c, rest)
+		case "MASTER":
+			s.cmdSentinelMaster(c, rest)
+		case "SLAVES", "REPLICAS":
+			s.cmdSentinelSlaves(c, rest)
+		case "GET-MASTER-ADDR-BY-NAME":
+			s.cmdSentinelGetMasterAddrByName(c, rest)
+		case "FAILOVER":
+			s.cmdSentinelFailover(c, rest)
+		case "MONITOR":
+			s.cmdSentinelMonitor(c, rest)
+		case "REMOVE":
+			s.cmdSentinelRemove(c, rest)
+		case "RESET":
+			s.cmdSentinelReset(c, rest)
+		case "SET":
+			s.cmdSentinelSet(c, rest)
+		case "SENTINELS":
+			s.cmdSentinelSentinels(c, rest)
+		case "CKQUORUM":
+			s.cmdSentinelCkquorum(c, rest)
+		case "CONFIG":
+			s.cmdSentinelConfig(c, rest)
+		default:
+			c.WriteError("ERR Unknown sentinel subcommand '" + args[0] + "'")
+		}
+	})
+}
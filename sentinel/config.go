@@ -0,0 +1,52 @@
+package sentinel
+
+import (
+	"strings"
+
+	"github.com/alicebob/miniredis/server"
+)
+
+// cmdSentinelConfig implements SENTINEL CONFIG GET/SET <parameter> [<value>],
+// sentinel-wide config knobs, as opposed to SENTINEL SET which is scoped to
+// a single monitored master. Setting "requirepass" is equivalent to calling
+// RequireSentinelAuth, so the sentinel's AUTH password can be configured
+// over the wire too. There is no sentinel-side username: AUTH takes the
+// password as its only argument and rejects the AUTH <user> <password> form.
+func (s *Sentinel) cmdSentinelConfig(c *server.Peer, args []string) {
+	if len(args) < 2 {
+		c.WriteError(errWrongNumber("SENTINEL CONFIG"))
+		return
+	}
+	action, param := strings.ToUpper(args[0]), strings.ToLower(args[1])
+	switch action {
+	case "GET":
+		s.Lock()
+		val, ok := s.config[param]
+		s.Unlock()
+		if !ok {
+			c.WriteLen(0)
+			return
+		}
+		c.WriteLen(2)
+		c.WriteBulk(param)
+		c.WriteBulk(val)
+	case "SET":
+		if len(args) != 3 {
+			c.WriteError(errWrongNumber("SENTINEL CONFIG SET"))
+			return
+		}
+		val := args[2]
+		s.Lock()
+		if s.config == nil {
+			s.config = map[string]string{}
+		}
+		s.config[param] = val
+		s.Unlock()
+		if param == "requirepass" {
+			s.RequireSentinelAuth(val)
+		}
+		c.WriteOK()
+	default:
+		c.WriteError("ERR Unknown SENTINEL CONFIG subcommand")
+	}
+}
@@ -0,0 +1,106 @@
+package sentinel
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/alicebob/miniredis"
+	"github.com/alicebob/miniredis/server"
+)
+
+// Failover promotes the first configured replica of masterName to be the
+// new master and demotes the old master to a replica, then publishes the
+// +switch-master event a real Sentinel emits once the promotion completes.
+func (s *Sentinel) Failover(masterName string) error {
+	s.Lock()
+	mm, ok := s.monitored[masterName]
+	if !ok {
+		s.Unlock()
+		return fmt.Errorf("sentinel: no such master %q", masterName)
+	}
+	if len(mm.replicas) == 0 {
+		s.Unlock()
+		return fmt.Errorf("sentinel: no replicas available to promote for %q", masterName)
+	}
+
+	oldMaster := mm.master
+	newMaster := mm.replicas[0]
+	newReplicas := make([]*miniredis.Miniredis, 0, len(mm.replicas))
+	if oldMaster != nil {
+		newReplicas = append(newReplicas, oldMaster)
+	}
+	newReplicas = append(newReplicas, mm.replicas[1:]...)
+
+	mm.master = newMaster
+	mm.replicas = newReplicas
+	s.Unlock()
+
+	var oldHost, oldPort string
+	if oldMaster != nil {
+		oldHost, oldPort = oldMaster.Host(), oldMaster.Port()
+	}
+	s.Publish("+switch-master", fmt.Sprintf(
+		"+switch-master %s %s %s %s %s",
+		masterName,
+		oldHost, oldPort,
+		newMaster.Host(), newMaster.Port(),
+	))
+	return nil
+}
+
+// TriggerSDown toggles the subjectively-down state for masterName and
+// publishes the matching +sdown/-sdown event, without waiting for a real
+// down-after-milliseconds timeout to elapse.
+func (s *Sentinel) TriggerSDown(masterName string) {
+	s.Lock()
+	mm, ok := s.monitored[masterName]
+	if !ok {
+		s.Unlock()
+		return
+	}
+	mm.sdown = !mm.sdown
+	down := mm.sdown
+	host, port := s.srv.Addr().IP.String(), strconv.Itoa(s.srv.Addr().Port)
+	s.Unlock()
+
+	event := "+sdown"
+	if !down {
+		event = "-sdown"
+	}
+	s.Publish(event, fmt.Sprintf("%s master %s %s %s", event, masterName, host, port))
+}
+
+// TriggerODown toggles the objectively-down state for masterName and
+// publishes the matching +odown/-odown event, without requiring a quorum of
+// real sentinels to agree.
+func (s *Sentinel) TriggerODown(masterName string) {
+	s.Lock()
+	mm, ok := s.monitored[masterName]
+	if !ok {
+		s.Unlock()
+		return
+	}
+	mm.odown = !mm.odown
+	down := mm.odown
+	host, port := s.srv.Addr().IP.String(), strconv.Itoa(s.srv.Addr().Port)
+	s.Unlock()
+
+	event := "+odown"
+	if !down {
+		event = "-odown"
+	}
+	s.Publish(event, fmt.Sprintf("%s master %s %s %s", event, masterName, host, port))
+}
+
+// cmdSentinelFailover implements SENTINEL FAILOVER <name>.
+func (s *Sentinel) cmdSentinelFailover(c *server.Peer, args []string) {
+	if len(args) != 1 {
+		c.WriteError(errWrongNumber("SENTINEL FAILOVER"))
+		return
+	}
+	if err := s.Failover(args[0]); err != nil {
+		c.WriteError("ERR " + err.Error())
+		return
+	}
+	c.WriteOK()
+}
@@ -0,0 +1,75 @@
+package sentinel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+)
+
+func TestFailoverPromotesReplica(t *testing.T) {
+	master := miniredis.RunT(t)
+	replica := miniredis.RunT(t)
+
+	s := NewSentinel(WithMasterName("mymaster"), WithMaster(master), WithReplicas([]*miniredis.Miniredis{replica}))
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.Failover("mymaster"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Master(); got != replica {
+		t.Fatalf("Master() = %v, want %v", got, replica)
+	}
+	if got := s.Replicas(); len(got) != 1 || got[0] != master {
+		t.Fatalf("Replicas() = %v, want [old master]", got)
+	}
+
+	if err := s.Failover("no-such-master"); err == nil {
+		t.Fatal("Failover on unknown master should error")
+	}
+}
+
+func TestFailoverWithNoMasterYet(t *testing.T) {
+	replica := miniredis.RunT(t)
+	s := NewSentinel()
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	s.Monitor("mymaster", nil, []*miniredis.Miniredis{replica})
+
+	if err := s.Failover("mymaster"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.monitored["mymaster"].master; got != replica {
+		t.Fatalf("master after failover = %v, want %v", got, replica)
+	}
+}
+
+func TestTriggerSDownODownDoNotDeadlock(t *testing.T) {
+	master := miniredis.RunT(t)
+	s := NewSentinel(WithMasterName("mymaster"), WithMaster(master))
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	done := make(chan struct{})
+	go func() {
+		s.TriggerSDown("mymaster")
+		s.TriggerSDown("mymaster")
+		s.TriggerODown("mymaster")
+		s.TriggerODown("mymaster")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("TriggerSDown/TriggerODown deadlocked")
+	}
+}
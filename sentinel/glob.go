@@ -0,0 +1,74 @@
+package sentinel
+
+import "strings"
+
+// globMatch reports whether s matches the redis glob-style pattern, which
+// supports '*' (any run of characters), '?' (any single character) and
+// '[...]' character classes. It backs SENTINEL RESET and PSUBSCRIBE.
+func globMatch(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if globMatch(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			s = s[1:]
+			pattern = pattern[1:]
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			end := strings.IndexByte(pattern, ']')
+			if end < 0 {
+				return pattern[1:] == s // malformed class, fall back to literal-ish match
+			}
+			class := pattern[1:end]
+			negate := false
+			if len(class) > 0 && class[0] == '^' {
+				negate = true
+				class = class[1:]
+			}
+			if classMatch(class, s[0]) == negate {
+				return false
+			}
+			s = s[1:]
+			pattern = pattern[end+1:]
+		default:
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+			s = s[1:]
+			pattern = pattern[1:]
+		}
+	}
+	return len(s) == 0
+}
+
+func classMatch(class string, b byte) bool {
+	for i := 0; i < len(class); i++ {
+		if i+2 < len(class) && class[i+1] == '-' {
+			if class[i] <= b && b <= class[i+2] {
+				return true
+			}
+			i += 2
+			continue
+		}
+		if class[i] == b {
+			return true
+		}
+	}
+	return false
+}
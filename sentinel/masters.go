@@ -0,0 +1,132 @@
+package sentinel
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/alicebob/miniredis"
+	"github.com/alicebob/miniredis/server"
+)
+
+// masterFields builds the flat name/value list redis-sentinel replies with
+// for SENTINEL MASTER(S), e.g. ["name", "mymaster", "ip", "127.0.0.1", ...].
+func masterFields(name string, m *miniredis.Miniredis, numSlaves, quorum int) []string {
+	return []string{
+		"name", name,
+		"ip", m.Host(),
+		"port", m.Port(),
+		"flags", "master",
+		"role-reported", "master",
+		"num-slaves", strconv.Itoa(numSlaves),
+		"num-other-sentinels", "0",
+		"quorum", strconv.Itoa(quorum),
+	}
+}
+
+func writeFields(c *server.Peer, fields []string) {
+	c.WriteLen(len(fields))
+	for _, f := range fields {
+		c.WriteBulk(f)
+	}
+}
+
+// cmdSentinelMasters implements SENTINEL MASTERS.
+func (s *Sentinel) cmdSentinelMasters(c *server.Peer, args []string) {
+	if len(args) != 0 {
+		c.WriteError(errWrongNumber("SENTINEL MASTERS"))
+		return
+	}
+	s.Lock()
+	names := make([]string, 0, len(s.monitored))
+	for name := range s.monitored {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var all [][]string
+	for _, name := range names {
+		mm := s.monitored[name]
+		if mm.master == nil {
+			continue
+		}
+		all = append(all, masterFields(name, mm.master, len(mm.replicas), mm.quorum))
+	}
+	s.Unlock()
+
+	c.WriteLen(len(all))
+	for _, fields := range all {
+		writeFields(c, fields)
+	}
+}
+
+// cmdSentinelMaster implements SENTINEL MASTER <name>.
+func (s *Sentinel) cmdSentinelMaster(c *server.Peer, args []string) {
+	if len(args) != 1 {
+		c.WriteError(errWrongNumber("SENTINEL MASTER"))
+		return
+	}
+	s.Lock()
+	mm, ok := s.monitored[args[0]]
+	var fields []string
+	if ok && mm.master != nil {
+		fields = masterFields(args[0], mm.master, len(mm.replicas), mm.quorum)
+	}
+	s.Unlock()
+
+	if fields == nil {
+		c.WriteError("ERR No such master with that name")
+		return
+	}
+	writeFields(c, fields)
+}
+
+// cmdSentinelSlaves implements SENTINEL SLAVES/REPLICAS <name>.
+func (s *Sentinel) cmdSentinelSlaves(c *server.Peer, args []string) {
+	if len(args) != 1 {
+		c.WriteError(errWrongNumber("SENTINEL SLAVES"))
+		return
+	}
+	s.Lock()
+	mm, ok := s.monitored[args[0]]
+	if !ok || mm.master == nil {
+		s.Unlock()
+		c.WriteError("ERR No such master with that name")
+		return
+	}
+	master, replicas := mm.master, mm.replicas
+	s.Unlock()
+
+	c.WriteLen(len(replicas))
+	for _, r := range replicas {
+		writeFields(c, []string{
+			"name", r.Host() + ":" + r.Port(),
+			"ip", r.Host(),
+			"port", r.Port(),
+			"runid", s.instanceRunID(r),
+			"flags", "slave",
+			"master-host", master.Host(),
+			"master-port", master.Port(),
+			"role-reported", "slave",
+			"master-link-status", "ok",
+			"slave-priority", "100",
+		})
+	}
+}
+
+// cmdSentinelGetMasterAddrByName implements SENTINEL GET-MASTER-ADDR-BY-NAME <name>.
+func (s *Sentinel) cmdSentinelGetMasterAddrByName(c *server.Peer, args []string) {
+	if len(args) != 1 {
+		c.WriteError(errWrongNumber("SENTINEL GET-MASTER-ADDR-BY-NAME"))
+		return
+	}
+	s.Lock()
+	mm, ok := s.monitored[args[0]]
+	s.Unlock()
+
+	if !ok || mm.master == nil {
+		c.WriteLen(-1)
+		return
+	}
+	c.WriteLen(2)
+	c.WriteBulk(mm.master.Host())
+	c.WriteBulk(mm.master.Port())
+}
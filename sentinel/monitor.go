@@ -0,0 +1,198 @@
+package sentinel
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/alicebob/miniredis/server"
+)
+
+// monitoredMaster is everything a sentinel tracks about a single monitored
+// master: its current master and replicas, and the SENTINEL SET-able
+// config for that master.
+type monitoredMaster struct {
+	master    *miniredis.Miniredis
+	replicas  []*miniredis.Miniredis
+	quorum    int
+	downAfter time.Duration
+	sdown     bool
+	odown     bool
+	authUser  string
+	authPass  string
+}
+
+// Monitor starts monitoring a new master under name, as `SENTINEL MONITOR`
+// would. If name was already monitored its whole entry is replaced: besides
+// the master and replicas, its quorum, auth and sdown/odown state go back to
+// their defaults.
+func (s *Sentinel) Monitor(name string, m *miniredis.Miniredis, replicas []*miniredis.Miniredis) {
+	s.Lock()
+	s.monitored[name] = &monitoredMaster{
+		master:   m,
+		replicas: replicas,
+		quorum:   1,
+	}
+	s.Unlock()
+	if m != nil {
+		s.wireInstance(name, m)
+	}
+	for _, r := range replicas {
+		s.wireInstance(name, r)
+	}
+}
+
+// WithMasterAuth records the username/password clients should use to
+// connect to the master monitored under masterName, and requires that
+// password on the master itself so the two stay in sync.
+func (s *Sentinel) WithMasterAuth(masterName, user, pass string) {
+	s.Lock()
+	mm, ok := s.monitored[masterName]
+	if !ok {
+		mm = &monitoredMaster{quorum: 1}
+		s.monitored[masterName] = mm
+	}
+	mm.authUser = user
+	mm.authPass = pass
+	master := mm.master
+	s.Unlock()
+
+	if master != nil {
+		master.RequireAuth(pass)
+	}
+}
+
+// RemoveMonitored stops monitoring name, as `SENTINEL REMOVE` would.
+func (s *Sentinel) RemoveMonitored(name string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.monitored, name)
+}
+
+// ResetMonitored clears the sdown/odown state of every monitored master
+// whose name matches pattern, as `SENTINEL RESET` would, and returns how
+// many masters matched.
+func (s *Sentinel) ResetMonitored(pattern string) int {
+	s.Lock()
+	defer s.Unlock()
+	n := 0
+	for name, mm := range s.monitored {
+		if !globMatch(pattern, name) {
+			continue
+		}
+		mm.sdown = false
+		mm.odown = false
+		n++
+	}
+	return n
+}
+
+// findKnownInstance looks for a *miniredis.Miniredis, among the masters and
+// replicas already monitored, listening at ip:port. It must be called with
+// s locked.
+func (s *Sentinel) findKnownInstance(ip, port string) *miniredis.Miniredis {
+	for _, mm := range s.monitored {
+		if mm.master != nil && mm.master.Host() == ip && mm.master.Port() == port {
+			return mm.master
+		}
+		for _, r := range mm.replicas {
+			if r.Host() == ip && r.Port() == port {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// cmdSentinelMonitor implements SENTINEL MONITOR <name> <ip> <port> <quorum>.
+func (s *Sentinel) cmdSentinelMonitor(c *server.Peer, args []string) {
+	if len(args) != 4 {
+		c.WriteError(errWrongNumber("SENTINEL MONITOR"))
+		return
+	}
+	name, ip, port := args[0], args[1], args[2]
+	quorum, err := strconv.Atoi(args[3])
+	if err != nil || quorum < 1 {
+		c.WriteError("ERR Invalid quorum")
+		return
+	}
+
+	s.Lock()
+	m := s.findKnownInstance(ip, port)
+	if m == nil {
+		s.Unlock()
+		c.WriteError("ERR I don't know about any " + ip + ":" + port + "; register it with Sentinel.Monitor() first")
+		return
+	}
+	s.monitored[name] = &monitoredMaster{master: m, quorum: quorum}
+	s.Unlock()
+	s.wireInstance(name, m)
+
+	c.WriteOK()
+}
+
+// cmdSentinelRemove implements SENTINEL REMOVE <name>.
+func (s *Sentinel) cmdSentinelRemove(c *server.Peer, args []string) {
+	if len(args) != 1 {
+		c.WriteError(errWrongNumber("SENTINEL REMOVE"))
+		return
+	}
+	s.RemoveMonitored(args[0])
+	c.WriteOK()
+}
+
+// cmdSentinelReset implements SENTINEL RESET <pattern>.
+func (s *Sentinel) cmdSentinelReset(c *server.Peer, args []string) {
+	if len(args) != 1 {
+		c.WriteError(errWrongNumber("SENTINEL RESET"))
+		return
+	}
+	c.WriteInt(s.ResetMonitored(args[0]))
+}
+
+// cmdSentinelSet implements SENTINEL SET <name> <option> <value>.
+func (s *Sentinel) cmdSentinelSet(c *server.Peer, args []string) {
+	if len(args) != 3 {
+		c.WriteError(errWrongNumber("SENTINEL SET"))
+		return
+	}
+	name, opt, val := args[0], args[1], args[2]
+
+	s.Lock()
+	mm, ok := s.monitored[name]
+	if !ok {
+		s.Unlock()
+		c.WriteError("ERR No such master with that name")
+		return
+	}
+	var master *miniredis.Miniredis
+	switch opt {
+	case "quorum":
+		q, err := strconv.Atoi(val)
+		if err != nil {
+			s.Unlock()
+			c.WriteError("ERR Invalid quorum")
+			return
+		}
+		mm.quorum = q
+	case "down-after-milliseconds":
+		ms, err := strconv.Atoi(val)
+		if err != nil {
+			s.Unlock()
+			c.WriteError("ERR Invalid down-after-milliseconds")
+			return
+		}
+		mm.downAfter = time.Duration(ms) * time.Millisecond
+	case "auth-user":
+		mm.authUser = val
+	case "auth-pass":
+		mm.authPass = val
+		master = mm.master
+	}
+	s.Unlock()
+
+	if master != nil {
+		master.RequireAuth(val)
+	}
+	c.WriteOK()
+}
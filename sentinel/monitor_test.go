@@ -0,0 +1,125 @@
+package sentinel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/gomodule/redigo/redis"
+)
+
+func TestMonitorRemoveReset(t *testing.T) {
+	master := miniredis.RunT(t)
+	s := NewSentinel()
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	s.Monitor("other", master, nil)
+	if got := s.monitored["other"].master; got != master {
+		t.Fatalf("Monitor did not register master, got %v", got)
+	}
+
+	s.TriggerSDown("other")
+	if !s.monitored["other"].sdown {
+		t.Fatal("expected sdown to be set before reset")
+	}
+
+	if n := s.ResetMonitored("oth*"); n != 1 {
+		t.Fatalf("ResetMonitored matched %d masters, want 1", n)
+	}
+	if s.monitored["other"].sdown {
+		t.Fatal("ResetMonitored should have cleared sdown")
+	}
+
+	s.RemoveMonitored("other")
+	if _, ok := s.monitored["other"]; ok {
+		t.Fatal("RemoveMonitored did not remove the master")
+	}
+}
+
+func TestMonitorAddReplicaSetReplicasDoNotDeadlock(t *testing.T) {
+	master := miniredis.RunT(t)
+	replica := miniredis.RunT(t)
+	s := NewSentinel(WithMasterName("mymaster"), WithMaster(master))
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	done := make(chan struct{})
+	go func() {
+		s.Monitor("other", master, nil)
+		s.WithMaster(master)
+		s.AddReplica(replica)
+		s.SetReplicas([]*miniredis.Miniredis{replica})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Monitor/WithMaster/AddReplica/SetReplicas deadlocked")
+	}
+}
+
+func TestWireSentinelMonitorWiresRoleInfo(t *testing.T) {
+	master := miniredis.RunT(t)
+	s := NewSentinel(WithMasterName("mymaster"), WithMaster(master))
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	c, err := redis.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.Do("SENTINEL", "MONITOR", "other", master.Host(), master.Port(), "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	mc, err := redis.Dial("tcp", master.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mc.Close()
+
+	role, err := redis.Values(mc.Do("ROLE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(role[0].([]byte)); got != "master" {
+		t.Fatalf("ROLE after SENTINEL MONITOR = %q, want %q", got, "master")
+	}
+}
+
+func TestWithMasterAuth(t *testing.T) {
+	master := miniredis.RunT(t)
+	s := NewSentinel(WithMasterName("mymaster"), WithMaster(master))
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	s.WithMasterAuth("mymaster", "default", "s3cret")
+
+	c, err := redis.Dial("tcp", master.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.Do("PING"); err == nil {
+		t.Fatal("expected master to require auth after WithMasterAuth")
+	}
+	if _, err := c.Do("AUTH", "s3cret"); err != nil {
+		t.Fatalf("AUTH with the password set via WithMasterAuth failed: %v", err)
+	}
+	if _, err := c.Do("PING"); err != nil {
+		t.Fatalf("PING after AUTH failed: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package sentinel
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/alicebob/miniredis"
+	"github.com/alicebob/miniredis/server"
+)
+
+// newRunID generates a 40 hex character run id, the same length redis
+// itself uses to identify a server instance.
+func newRunID() string {
+	buf := make([]byte, 20)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// RunID returns the unique id this sentinel identifies itself with, as
+// found in the `runid` field of its peers' SENTINEL SENTINELS replies.
+func (s *Sentinel) RunID() string {
+	return s.runid
+}
+
+// AddPeer registers other as a fellow sentinel watching the same masters,
+// so it shows up in this sentinel's SENTINEL SENTINELS and SENTINEL
+// CKQUORUM replies. It is one-directional; use Cluster to wire up a full
+// mesh of peers.
+func (s *Sentinel) AddPeer(other *Sentinel) {
+	s.Lock()
+	defer s.Unlock()
+	s.peers = append(s.peers, other)
+}
+
+// Cluster starts n Sentinels, all monitoring the given master and replicas
+// under masterName, and registers each one as a peer of every other, the
+// way a real sentinel deployment gossips about its peers. It is meant for
+// tests that want to exercise a FailoverClient against a realistic quorum
+// of sentinels, some of which may later be Close()d to simulate a partial
+// outage.
+func Cluster(n int, masterName string, master *miniredis.Miniredis, replicas []*miniredis.Miniredis) ([]*Sentinel, error) {
+	sentinels := make([]*Sentinel, 0, n)
+	for i := 0; i < n; i++ {
+		sn := NewSentinel(WithMasterName(masterName), WithMaster(master), WithReplicas(replicas))
+		if err := sn.Start(); err != nil {
+			return nil, fmt.Errorf("sentinel: starting peer %d: %w", i, err)
+		}
+		sentinels = append(sentinels, sn)
+	}
+	for _, a := range sentinels {
+		for _, b := range sentinels {
+			if a != b {
+				a.AddPeer(b)
+			}
+		}
+	}
+	return sentinels, nil
+}
+
+// cmdSentinelSentinels implements SENTINEL SENTINELS <name>.
+func (s *Sentinel) cmdSentinelSentinels(c *server.Peer, args []string) {
+	if len(args) != 1 {
+		c.WriteError(errWrongNumber("SENTINEL SENTINELS"))
+		return
+	}
+	s.Lock()
+	_, ok := s.monitored[args[0]]
+	peers := append([]*Sentinel{}, s.peers...)
+	s.Unlock()
+	if !ok {
+		c.WriteError("ERR No such master with that name")
+		return
+	}
+
+	var all [][]string
+	for _, p := range peers {
+		if !p.isRunning() {
+			continue
+		}
+		all = append(all, []string{
+			"name", p.Host() + ":" + p.Port(),
+			"ip", p.Host(),
+			"port", p.Port(),
+			"runid", p.RunID(),
+			"flags", "sentinel",
+		})
+	}
+
+	c.WriteLen(len(all))
+	for _, fields := range all {
+		writeFields(c, fields)
+	}
+}
+
+// cmdSentinelCkquorum implements SENTINEL CKQUORUM <name>.
+func (s *Sentinel) cmdSentinelCkquorum(c *server.Peer, args []string) {
+	if len(args) != 1 {
+		c.WriteError(errWrongNumber("SENTINEL CKQUORUM"))
+		return
+	}
+	s.Lock()
+	mm, ok := s.monitored[args[0]]
+	var quorum int
+	if ok {
+		quorum = mm.quorum
+	}
+	peers := append([]*Sentinel{}, s.peers...)
+	s.Unlock()
+	if !ok {
+		c.WriteError("ERR No such master with that name")
+		return
+	}
+
+	votes := 1 // this sentinel counts itself
+	for _, p := range peers {
+		if p.isRunning() {
+			votes++
+		}
+	}
+
+	if votes >= quorum {
+		c.WriteInline(fmt.Sprintf("OK %d/%d", votes, quorum))
+		return
+	}
+	c.WriteError(fmt.Sprintf("NOQUORUM %d/%d usable Sentinels", votes, quorum))
+}
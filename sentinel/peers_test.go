@@ -0,0 +1,52 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis"
+	"github.com/gomodule/redigo/redis"
+)
+
+func TestSentinelsAndCkquorum(t *testing.T) {
+	master := miniredis.RunT(t)
+
+	s1 := NewSentinel(WithMasterName("mymaster"), WithMaster(master))
+	if err := s1.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s1.Close()
+
+	s2 := NewSentinel(WithMasterName("mymaster"), WithMaster(master))
+	if err := s2.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s2.Close()
+
+	s1.AddPeer(s2)
+
+	c, err := redis.Dial("tcp", s1.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	sentinels, err := redis.Values(c.Do("SENTINEL", "SENTINELS", "mymaster"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sentinels) != 1 {
+		t.Fatalf("SENTINEL SENTINELS returned %d peers, want 1", len(sentinels))
+	}
+
+	if _, err := c.Do("SENTINEL", "SET", "mymaster", "quorum", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if reply, err := redis.String(c.Do("SENTINEL", "CKQUORUM", "mymaster")); err != nil || reply != "OK 2/2" {
+		t.Fatalf("SENTINEL CKQUORUM = %q, %v, want %q, nil", reply, err, "OK 2/2")
+	}
+
+	s2.Close()
+	if _, err := c.Do("SENTINEL", "CKQUORUM", "mymaster"); err == nil {
+		t.Fatal("expected NOQUORUM error once a peer is gone")
+	}
+}
@@ -0,0 +1,251 @@
+package sentinel
+
+import (
+	"github.com/alicebob/miniredis"
+	"github.com/alicebob/miniredis/server"
+)
+
+// subscription is the pub/sub state kept for a single connection: the
+// literal channels it SUBSCRIBEd to, and the glob patterns it PSUBSCRIBEd
+// to.
+type subscription struct {
+	channels map[string]bool
+	patterns map[string]bool
+}
+
+func (sub *subscription) count() int {
+	return len(sub.channels) + len(sub.patterns)
+}
+
+// Publish delivers message to every connection subscribed to channel,
+// directly or via a matching PSUBSCRIBE pattern, the way a real Sentinel
+// relays +switch-master, +sdown and similar events. It returns the number
+// of deliveries made: a connection that matches through several
+// subscriptions (a channel and a pattern, or two patterns) is counted once
+// per match.
+func (s *Sentinel) Publish(channel, message string) int {
+	type patternMatch struct {
+		peer    *server.Peer
+		pattern string
+	}
+
+	s.Lock()
+	var direct []*server.Peer
+	var patterns []patternMatch
+	for c, sub := range s.subs {
+		if sub.channels[channel] {
+			direct = append(direct, c)
+		}
+		for p := range sub.patterns {
+			if globMatch(p, channel) {
+				patterns = append(patterns, patternMatch{c, p})
+			}
+		}
+	}
+	s.Unlock()
+
+	for _, c := range direct {
+		c.Block(func(w *server.Writer) {
+			w.WriteLen(3)
+			w.WriteBulk("message")
+			w.WriteBulk(channel)
+			w.WriteBulk(message)
+			w.Flush()
+		})
+	}
+	for _, pm := range patterns {
+		c, pattern := pm.peer, pm.pattern
+		c.Block(func(w *server.Writer) {
+			w.WriteLen(4)
+			w.WriteBulk("pmessage")
+			w.WriteBulk(pattern)
+			w.WriteBulk(channel)
+			w.WriteBulk(message)
+			w.Flush()
+		})
+	}
+	return len(direct) + len(patterns)
+}
+
+// NotifySwitchMaster publishes a +switch-master event, as Failover does,
+// without changing which instance is actually the master.
+func (s *Sentinel) NotifySwitchMaster(masterName, oldHost, oldPort, newHost, newPort string) {
+	s.Publish("+switch-master", "+switch-master "+masterName+" "+oldHost+" "+oldPort+" "+newHost+" "+newPort)
+}
+
+// NotifySDown publishes a +sdown event for masterName without touching the
+// sdown state TriggerSDown tracks.
+func (s *Sentinel) NotifySDown(masterName string) {
+	s.Publish("+sdown", "+sdown master "+masterName+" "+s.Host()+" "+s.Port())
+}
+
+// NotifyReplicaAdded publishes a +slave event, as real Sentinel does when it
+// discovers a new replica of masterName via INFO.
+func (s *Sentinel) NotifyReplicaAdded(masterName string, r *miniredis.Miniredis) {
+	s.Publish("+slave", "+slave slave "+r.Host()+":"+r.Port()+" "+r.Host()+" "+r.Port()+" @ "+masterName+" "+s.Host()+" "+s.Port())
+}
+
+// commandsPubsub registers SUBSCRIBE, UNSUBSCRIBE, PSUBSCRIBE and
+// PUNSUBSCRIBE, the subset of the pub/sub protocol clients rely on to be
+// notified of topology changes.
+func commandsPubsub(s *Sentinel) {
+	s.srv.Register("SUBSCRIBE", func(c *server.Peer, cmd string, args []string) {
+		if !s.handleAuth(c) {
+			return
+		}
+		if len(args) < 1 {
+			c.WriteError(errWrongNumber(cmd))
+			return
+		}
+		for _, channel := range args {
+			s.subscribe(c, channel, false)
+		}
+	})
+
+	s.srv.Register("UNSUBSCRIBE", func(c *server.Peer, cmd string, args []string) {
+		if !s.handleAuth(c) {
+			return
+		}
+		channels := args
+		if len(channels) == 0 {
+			channels = s.subscribedChannels(c)
+		}
+		if len(channels) == 0 {
+			c.WriteLen(3)
+			c.WriteBulk("unsubscribe")
+			c.WriteNull()
+			c.WriteInt(0)
+			return
+		}
+		for _, channel := range channels {
+			s.unsubscribe(c, channel, false)
+		}
+	})
+
+	s.srv.Register("PSUBSCRIBE", func(c *server.Peer, cmd string, args []string) {
+		if !s.handleAuth(c) {
+			return
+		}
+		if len(args) < 1 {
+			c.WriteError(errWrongNumber(cmd))
+			return
+		}
+		for _, pattern := range args {
+			s.subscribe(c, pattern, true)
+		}
+	})
+
+	s.srv.Register("PUNSUBSCRIBE", func(c *server.Peer, cmd string, args []string) {
+		if !s.handleAuth(c) {
+			return
+		}
+		patterns := args
+		if len(patterns) == 0 {
+			patterns = s.subscribedPatterns(c)
+		}
+		if len(patterns) == 0 {
+			c.WriteLen(3)
+			c.WriteBulk("punsubscribe")
+			c.WriteNull()
+			c.WriteInt(0)
+			return
+		}
+		for _, pattern := range patterns {
+			s.unsubscribe(c, pattern, true)
+		}
+	})
+}
+
+// subOf returns c's subscription state, creating and registering it (along
+// with disconnect cleanup) the first time c subscribes or unsubscribes.
+func (s *Sentinel) subOf(c *server.Peer) *subscription {
+	if s.subs == nil {
+		s.subs = map[*server.Peer]*subscription{}
+	}
+	sub, ok := s.subs[c]
+	if !ok {
+		sub = &subscription{channels: map[string]bool{}, patterns: map[string]bool{}}
+		s.subs[c] = sub
+		c.OnDisconnect(func() {
+			s.Lock()
+			delete(s.subs, c)
+			s.Unlock()
+		})
+	}
+	return sub
+}
+
+// subscribe adds channel (literal, or a glob pattern if pattern is set) to
+// c's subscriptions and acknowledges it the way redis does: an array of
+// [kind, channel, subscription count].
+func (s *Sentinel) subscribe(c *server.Peer, channel string, pattern bool) {
+	s.Lock()
+	sub := s.subOf(c)
+	kind := "subscribe"
+	if pattern {
+		kind = "psubscribe"
+		sub.patterns[channel] = true
+	} else {
+		sub.channels[channel] = true
+	}
+	count := sub.count()
+	s.Unlock()
+
+	c.WriteLen(3)
+	c.WriteBulk(kind)
+	c.WriteBulk(channel)
+	c.WriteInt(count)
+}
+
+// unsubscribe removes channel (literal, or a glob pattern if pattern is
+// set) from c's subscriptions and acknowledges it the way redis does.
+func (s *Sentinel) unsubscribe(c *server.Peer, channel string, pattern bool) {
+	s.Lock()
+	sub := s.subOf(c)
+	kind := "unsubscribe"
+	if pattern {
+		kind = "punsubscribe"
+		delete(sub.patterns, channel)
+	} else {
+		delete(sub.channels, channel)
+	}
+	count := sub.count()
+	s.Unlock()
+
+	c.WriteLen(3)
+	c.WriteBulk(kind)
+	c.WriteBulk(channel)
+	c.WriteInt(count)
+}
+
+// subscribedChannels returns the literal channels c currently subscribes
+// to, for a bare UNSUBSCRIBE with no arguments.
+func (s *Sentinel) subscribedChannels(c *server.Peer) []string {
+	s.Lock()
+	defer s.Unlock()
+	sub, ok := s.subs[c]
+	if !ok {
+		return nil
+	}
+	channels := make([]string, 0, len(sub.channels))
+	for channel := range sub.channels {
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// subscribedPatterns returns the glob patterns c currently subscribes to,
+// for a bare PUNSUBSCRIBE with no arguments.
+func (s *Sentinel) subscribedPatterns(c *server.Peer) []string {
+	s.Lock()
+	defer s.Unlock()
+	sub, ok := s.subs[c]
+	if !ok {
+		return nil
+	}
+	patterns := make([]string, 0, len(sub.patterns))
+	for pattern := range sub.patterns {
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
@@ -0,0 +1,74 @@
+package sentinel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/gomodule/redigo/redis"
+)
+
+func TestSubscribePatternGlobMatch(t *testing.T) {
+	master := miniredis.RunT(t)
+	s := NewSentinel(WithMasterName("mymaster"), WithMaster(master))
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	conn, err := redis.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	psc := redis.PubSubConn{Conn: conn}
+
+	if err := psc.PSubscribe("+s*"); err != nil {
+		t.Fatal(err)
+	}
+	if sub, ok := psc.Receive().(redis.Subscription); !ok || sub.Kind != "psubscribe" || sub.Count != 1 {
+		t.Fatalf("unexpected psubscribe ack: %#v", sub)
+	}
+
+	if n := s.Publish("+sdown", "+sdown master mymaster 127.0.0.1 6379"); n != 1 {
+		t.Fatalf("Publish delivered to %d connections, want 1", n)
+	}
+
+	msg, ok := psc.Receive().(redis.Message)
+	if !ok || msg.Pattern != "+s*" || msg.Channel != "+sdown" {
+		t.Fatalf("unexpected pmessage: %#v", msg)
+	}
+
+	if n := s.Publish("+other", "irrelevant"); n != 0 {
+		t.Fatalf("Publish to a non-matching channel delivered to %d connections, want 0", n)
+	}
+}
+
+func TestBareUnsubscribeRepliesOnce(t *testing.T) {
+	master := miniredis.RunT(t)
+	s := NewSentinel(WithMasterName("mymaster"), WithMaster(master))
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	conn, err := redis.Dial("tcp", s.Addr(), redis.DialReadTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	reply, err := redis.Values(conn.Do("UNSUBSCRIBE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reply) != 3 {
+		t.Fatalf("UNSUBSCRIBE with no subscriptions replied with %d fields, want 3", len(reply))
+	}
+	if reply[1] != nil {
+		t.Fatalf("UNSUBSCRIBE channel = %v, want nil", reply[1])
+	}
+	if count, err := redis.Int(reply[2], nil); err != nil || count != 0 {
+		t.Fatalf("UNSUBSCRIBE count = %v, %v, want 0, nil", reply[2], err)
+	}
+}
@@ -0,0 +1,75 @@
+package sentinel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alicebob/miniredis"
+	"github.com/alicebob/miniredis/server"
+)
+
+// roleInfo reports the role instance currently plays for the master
+// monitored under name. It is computed live off s.monitored, so a
+// Failover() immediately flips what ROLE/INFO report for both the old
+// and the new master.
+func (s *Sentinel) roleInfo(name string, instance *miniredis.Miniredis) (role, masterHost, masterPort string) {
+	s.Lock()
+	defer s.Unlock()
+	mm, ok := s.monitored[name]
+	if !ok || mm.master == nil || instance == mm.master {
+		return "master", "", ""
+	}
+	return "slave", mm.master.Host(), mm.master.Port()
+}
+
+// instanceRunID returns the runid instance identifies itself with,
+// assigning one the first time it is seen.
+func (s *Sentinel) instanceRunID(instance *miniredis.Miniredis) string {
+	s.Lock()
+	defer s.Unlock()
+	id, ok := s.instanceIDs[instance]
+	if !ok {
+		id = newRunID()
+		s.instanceIDs[instance] = id
+	}
+	return id
+}
+
+// wireInstance registers a ROLE and INFO replication overlay on instance
+// reporting the role computed by s.roleInfo(name, instance).
+func (s *Sentinel) wireInstance(name string, instance *miniredis.Miniredis) {
+	s.instanceRunID(instance)
+	srv := instance.Server()
+
+	srv.Register("ROLE", func(c *server.Peer, cmd string, args []string) {
+		role, masterHost, masterPort := s.roleInfo(name, instance)
+		if role == "master" {
+			c.WriteLen(3)
+			c.WriteBulk("master")
+			c.WriteInt(0)
+			c.WriteLen(0)
+			return
+		}
+		port, _ := strconv.Atoi(masterPort)
+		c.WriteLen(5)
+		c.WriteBulk("slave")
+		c.WriteBulk(masterHost)
+		c.WriteInt(port)
+		c.WriteBulk("connected")
+		c.WriteInt(0)
+	})
+
+	srv.Register("INFO", func(c *server.Peer, cmd string, args []string) {
+		role, masterHost, masterPort := s.roleInfo(name, instance)
+		var b strings.Builder
+		b.WriteString("# Replication\r\n")
+		fmt.Fprintf(&b, "role:%s\r\n", role)
+		if role == "slave" {
+			fmt.Fprintf(&b, "master_host:%s\r\n", masterHost)
+			fmt.Fprintf(&b, "master_port:%s\r\n", masterPort)
+			b.WriteString("master_link_status:up\r\n")
+		}
+		c.WriteBulk(b.String())
+	})
+}
@@ -0,0 +1,51 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis"
+	"github.com/gomodule/redigo/redis"
+)
+
+func TestRoleFlipsAfterFailover(t *testing.T) {
+	master := miniredis.RunT(t)
+	replica := miniredis.RunT(t)
+
+	s := NewSentinel(WithMasterName("mymaster"), WithMaster(master), WithReplicas([]*miniredis.Miniredis{replica}))
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	assertRole := func(inst *miniredis.Miniredis, want string) {
+		t.Helper()
+		c, err := redis.Dial("tcp", inst.Addr())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		role, err := redis.Values(c.Do("ROLE"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(role[0].([]byte)); got != want {
+			t.Fatalf("ROLE = %q, want %q", got, want)
+		}
+	}
+
+	assertRole(master, "master")
+	assertRole(replica, "slave")
+
+	replicaID := s.instanceRunID(replica)
+
+	if err := s.Failover("mymaster"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertRole(master, "slave")
+	assertRole(replica, "master")
+
+	if got := s.instanceRunID(replica); got != replicaID {
+		t.Fatalf("instanceRunID changed across a failover: got %q, want %q", got, replicaID)
+	}
+}
@@ -17,12 +17,17 @@ func errWrongNumber(cmd string) string {
 // Sentinel - a redis sentinel server implementation.
 type Sentinel struct {
 	sync.Mutex
-	srv      *server.Server
-	port     int
-	password string
-	signal   *sync.Cond
-	master   *miniredis.Miniredis
-	replicas []*miniredis.Miniredis
+	srv         *server.Server
+	port        int
+	password    string
+	signal      *sync.Cond
+	masterName  string // name used by the single-master Master()/Replicas() API
+	monitored   map[string]*monitoredMaster
+	subs        map[*server.Peer]*subscription
+	runid       string
+	peers       []*Sentinel
+	config      map[string]string
+	instanceIDs map[*miniredis.Miniredis]string
 }
 
 // connCtx has all state for a single connection.
@@ -34,40 +39,78 @@ type connCtx struct {
 func NewSentinel(opts ...Option) *Sentinel {
 	s := Sentinel{}
 	s.signal = sync.NewCond(&s)
+	s.monitored = map[string]*monitoredMaster{}
+	s.runid = newRunID()
+	s.instanceIDs = map[*miniredis.Miniredis]string{}
 	o := GetOpts(opts...)
+	s.masterName = o.masterName
+	mm := &monitoredMaster{quorum: 1}
 	if o.master != nil {
-		s.master = o.master
-		s.replicas = []*miniredis.Miniredis{o.master} // set a reasonable default
+		mm.master = o.master
+		mm.replicas = []*miniredis.Miniredis{o.master} // set a reasonable default
 	}
 	if o.replicas != nil {
-		s.replicas = o.replicas
+		mm.replicas = o.replicas
+	}
+	s.monitored[s.masterName] = mm
+	if mm.master != nil {
+		s.wireInstance(s.masterName, mm.master)
+	}
+	for _, r := range mm.replicas {
+		s.wireInstance(s.masterName, r)
 	}
 	return &s
 }
 
-// WithMaster - set the master
+// WithMaster - set the master being monitored under MasterName().
 func (s *Sentinel) WithMaster(m *miniredis.Miniredis) {
-	s.master = m
+	s.Lock()
+	name := s.masterName
+	s.monitored[name].master = m
+	s.Unlock()
+	s.wireInstance(name, m)
 }
 
-// Master - get the master
+// Master - get the master being monitored under MasterName().
 func (s *Sentinel) Master() *miniredis.Miniredis {
-	return s.master
+	s.Lock()
+	defer s.Unlock()
+	return s.monitored[s.masterName].master
+}
+
+// MasterName - get the name the default master is monitored under.
+func (s *Sentinel) MasterName() string {
+	return s.masterName
 }
 
-// AddReplica - add a new replica to the existing ones
+// AddReplica - add a new replica to the master monitored under MasterName().
 func (s *Sentinel) AddReplica(r *miniredis.Miniredis) {
-	s.replicas = append(s.replicas, r)
+	s.Lock()
+	name := s.masterName
+	mm := s.monitored[name]
+	mm.replicas = append(mm.replicas, r)
+	s.Unlock()
+	s.wireInstance(name, r)
 }
 
-// SetReplicas - replace all the existing replicas
+// SetReplicas - replace all the replicas of the master monitored under
+// MasterName().
 func (s *Sentinel) SetReplicas(replicas []*miniredis.Miniredis) {
-	s.replicas = replicas
+	s.Lock()
+	name := s.masterName
+	s.monitored[name].replicas = replicas
+	s.Unlock()
+	for _, r := range replicas {
+		s.wireInstance(name, r)
+	}
 }
 
-// Replicas - get the current replicas
+// Replicas - get the current replicas of the master monitored under
+// MasterName().
 func (s *Sentinel) Replicas() []*miniredis.Miniredis {
-	return s.replicas
+	s.Lock()
+	defer s.Unlock()
+	return s.monitored[s.masterName].replicas
 }
 
 // Run creates and Start()s a Sentinel.
@@ -103,6 +146,7 @@ func (s *Sentinel) start(srv *server.Server) error {
 	s.port = srv.Addr().Port
 
 	commandsPing(s)
+	commandsPubsub(s)
 	return nil
 }
 
@@ -129,9 +173,10 @@ func (s *Sentinel) Close() {
 
 }
 
-// RequireAuth makes every connection need to AUTH first. Disable again by
-// setting an empty string.
-func (s *Sentinel) RequireAuth(pw string) {
+// RequireSentinelAuth makes every connection to the sentinel itself need to
+// AUTH first, independently of any per-master auth set via WithMasterAuth.
+// Disable again by setting an empty string.
+func (s *Sentinel) RequireSentinelAuth(pw string) {
 	s.Lock()
 	defer s.Unlock()
 	s.password = pw
@@ -159,6 +204,14 @@ func (s *Sentinel) Port() string {
 	return strconv.Itoa(s.srv.Addr().Port)
 }
 
+// isRunning reports whether this sentinel is currently Start()ed, so peers
+// can tell whether it would answer for SENTINEL SENTINELS/CKQUORUM purposes.
+func (s *Sentinel) isRunning() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.srv != nil
+}
+
 // CurrentConnectionCount returns the number of currently connected clients.
 func (s *Sentinel) CurrentConnectionCount() int {
 	s.Lock()